@@ -0,0 +1,461 @@
+// Command release builds the statusline release archives and the Homebrew
+// and Scoop metadata that points at them. It is kept out of the statusline
+// binary, which only needs what renders run. Run it from the repository root:
+//
+//	go run ./cmd/release build v1.2.3 dist
+//	go run ./cmd/release packages v1.2.3 dist
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	if err := run(os.Stdout, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run executes a release subcommand with the given arguments (excluding the
+// program name).
+func run(w io.Writer, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "build":
+			return buildRelease(w, args[1:])
+		case "packages":
+			return writePackages(w, args[1:])
+		}
+	}
+	return fmt.Errorf("Usage: go run ./cmd/release build|packages <version> [dir]")
+}
+
+// releaseTarget is one GOOS/GOARCH pair in the release build matrix.
+type releaseTarget struct {
+	GOOS   string
+	GOARCH string
+}
+
+var releaseTargets = []releaseTarget{
+	{"darwin", "amd64"},
+	{"darwin", "arm64"},
+	{"linux", "amd64"},
+	{"linux", "arm64"},
+	{"windows", "amd64"},
+	{"windows", "arm64"},
+}
+
+// releaseChecksumsFile lists "<sha256>  <archive>" for every release archive.
+const releaseChecksumsFile = "checksums.txt"
+
+func releaseBinaryName(goos string) string {
+	if goos == "windows" {
+		return "statusline.exe"
+	}
+	return "statusline"
+}
+
+// releaseArchiveName returns the archive name for a target, e.g.
+// statusline_1.2.3_darwin_arm64.tar.gz. Installers derive download URLs from it.
+func releaseArchiveName(version, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("statusline_%s_%s_%s%s", strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// buildRelease cross-compiles every target in releaseTargets into outDir
+// (default dist) and writes the checksums file.
+func buildRelease(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Usage: go run ./cmd/release build <version> [output-dir]")
+	}
+
+	releaseVersion := args[0]
+	outDir := "dist"
+	if len(args) > 1 {
+		outDir = args[1]
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	var archives []string
+	for _, target := range releaseTargets {
+		archive, err := buildReleaseArchive(releaseVersion, target, outDir)
+		if err != nil {
+			return fmt.Errorf("release %s/%s failed: %v", target.GOOS, target.GOARCH, err)
+		}
+		fmt.Fprintf(w, "📦 %s\n", archive)
+		archives = append(archives, archive)
+	}
+
+	if err := writeChecksums(outDir, archives); err != nil {
+		return fmt.Errorf("failed to write checksums: %v", err)
+	}
+	fmt.Fprintf(w, "🔒 %s\n", releaseChecksumsFile)
+
+	return nil
+}
+
+// buildReleaseArchive cross-compiles the current source tree for target and
+// packages the binary with README.md and LICENSE. It returns the archive name.
+func buildReleaseArchive(releaseVersion string, target releaseTarget, outDir string) (string, error) {
+	buildDir, err := os.MkdirTemp("", "statusline-release")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(buildDir)
+
+	binaryName := releaseBinaryName(target.GOOS)
+	binaryPath := filepath.Join(buildDir, binaryName)
+
+	cmd := exec.Command("go", "build", "-trimpath",
+		"-ldflags", "-s -w -X main.version="+releaseVersion,
+		"-o", binaryPath, ".")
+	cmd.Env = append(os.Environ(), "GOOS="+target.GOOS, "GOARCH="+target.GOARCH, "CGO_ENABLED=0")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("go build: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	files := map[string]string{binaryName: binaryPath}
+	for _, extra := range []string{"README.md", "LICENSE"} {
+		if _, err := os.Stat(extra); err == nil {
+			files[extra] = extra
+		}
+	}
+
+	archive := releaseArchiveName(releaseVersion, target.GOOS, target.GOARCH)
+	archivePath := filepath.Join(outDir, archive)
+	if target.GOOS == "windows" {
+		err = writeZipArchive(archivePath, files)
+	} else {
+		err = writeTarGzArchive(archivePath, files)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return archive, nil
+}
+
+// writeTarGzArchive writes files (archive name -> source path) to a .tar.gz.
+func writeTarGzArchive(archivePath string, files map[string]string) error {
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	for _, name := range sortedKeys(files) {
+		data, err := os.ReadFile(files[name])
+		if err != nil {
+			return err
+		}
+
+		info, err := os.Stat(files[name])
+		if err != nil {
+			return err
+		}
+
+		header := &tar.Header{
+			Name:    name,
+			Mode:    int64(info.Mode().Perm()),
+			Size:    int64(len(data)),
+			ModTime: info.ModTime(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// writeZipArchive writes files (archive name -> source path) to a .zip.
+func writeZipArchive(archivePath string, files map[string]string) error {
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+
+	for _, name := range sortedKeys(files) {
+		data, err := os.ReadFile(files[name])
+		if err != nil {
+			return err
+		}
+
+		entry, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// writeChecksums writes the sha256sum-compatible checksums file for archives in dir.
+func writeChecksums(dir string, archives []string) error {
+	var lines []string
+	for _, archive := range archives {
+		sum, err := fileSHA256(filepath.Join(dir, archive))
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s", sum, archive))
+	}
+
+	return os.WriteFile(filepath.Join(dir, releaseChecksumsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// releaseDownloadBaseURL is where release archives are published; the
+// version tag is appended as a path element.
+const releaseDownloadBaseURL = "https://github.com/tolluset/statusline/releases/download"
+
+var homebrewFormulaTemplate = template.Must(template.New("formula").Parse(`class Statusline < Formula
+  desc "Git and GitHub statusline for Claude Code"
+  homepage "https://github.com/tolluset/statusline"
+  version "{{.Version}}"
+  license "MIT"
+{{range .Platforms}}
+  on_{{.OS}} do
+{{- range .Arches}}
+    on_{{.Arch}} do
+      url "{{.URL}}"
+      sha256 "{{.SHA256}}"
+    end
+{{- end}}
+  end
+{{end}}
+  def install
+    bin.install "statusline"
+  end
+
+  test do
+    assert_match "GitHub Notifications", shell_output("#{bin}/statusline noti")
+  end
+end
+`))
+
+type homebrewArch struct {
+	Arch   string
+	URL    string
+	SHA256 string
+}
+
+type homebrewPlatform struct {
+	OS     string
+	Arches []homebrewArch
+}
+
+type scoopArch struct {
+	URL  string `json:"url"`
+	Hash string `json:"hash"`
+}
+
+type scoopManifest struct {
+	Version      string               `json:"version"`
+	Description  string               `json:"description"`
+	Homepage     string               `json:"homepage"`
+	License      string               `json:"license"`
+	Architecture map[string]scoopArch `json:"architecture"`
+	Bin          string               `json:"bin"`
+}
+
+// writePackages writes a Homebrew formula and Scoop manifest for the
+// archives listed in <dist-dir>/checksums.txt.
+func writePackages(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Usage: go run ./cmd/release packages <version> [dist-dir] [download-base-url]")
+	}
+
+	releaseVersion := args[0]
+	distDir := "dist"
+	if len(args) > 1 {
+		distDir = args[1]
+	}
+	baseURL := releaseDownloadBaseURL + "/" + releaseVersion
+	if len(args) > 2 {
+		baseURL = strings.TrimSuffix(args[2], "/")
+	}
+
+	sums, err := readChecksums(filepath.Join(distDir, releaseChecksumsFile))
+	if err != nil {
+		return fmt.Errorf("failed to read checksums: %v", err)
+	}
+
+	formula, err := homebrewFormula(releaseVersion, baseURL, sums)
+	if err != nil {
+		return err
+	}
+	formulaPath := filepath.Join(distDir, "statusline.rb")
+	if err := os.WriteFile(formulaPath, []byte(formula), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "🍺 %s\n", formulaPath)
+
+	manifest, err := scoopManifestJSON(releaseVersion, baseURL, sums)
+	if err != nil {
+		return err
+	}
+	manifestPath := filepath.Join(distDir, "statusline.json")
+	if err := os.WriteFile(manifestPath, manifest, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "🥄 %s\n", manifestPath)
+
+	return nil
+}
+
+// readChecksums parses a checksums file into archive name -> sha256.
+func readChecksums(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sums[fields[1]] = fields[0]
+	}
+	return sums, scanner.Err()
+}
+
+func homebrewFormula(releaseVersion, baseURL string, sums map[string]string) (string, error) {
+	var platforms []homebrewPlatform
+	for _, goos := range []string{"darwin", "linux"} {
+		platform := homebrewPlatform{OS: goos}
+		if goos == "darwin" {
+			platform.OS = "macos"
+		}
+
+		for _, target := range releaseTargets {
+			if target.GOOS != goos {
+				continue
+			}
+			archive := releaseArchiveName(releaseVersion, target.GOOS, target.GOARCH)
+			sum, ok := sums[archive]
+			if !ok {
+				return "", fmt.Errorf("missing checksum for %s", archive)
+			}
+
+			arch := "intel"
+			if target.GOARCH == "arm64" {
+				arch = "arm"
+			}
+			platform.Arches = append(platform.Arches, homebrewArch{
+				Arch:   arch,
+				URL:    baseURL + "/" + archive,
+				SHA256: sum,
+			})
+		}
+		platforms = append(platforms, platform)
+	}
+
+	var buf strings.Builder
+	err := homebrewFormulaTemplate.Execute(&buf, struct {
+		Version   string
+		Platforms []homebrewPlatform
+	}{strings.TrimPrefix(releaseVersion, "v"), platforms})
+	return buf.String(), err
+}
+
+func scoopManifestJSON(releaseVersion, baseURL string, sums map[string]string) ([]byte, error) {
+	manifest := scoopManifest{
+		Version:      strings.TrimPrefix(releaseVersion, "v"),
+		Description:  "Git and GitHub statusline for Claude Code",
+		Homepage:     "https://github.com/tolluset/statusline",
+		License:      "MIT",
+		Architecture: make(map[string]scoopArch),
+		Bin:          releaseBinaryName("windows"),
+	}
+
+	scoopArchNames := map[string]string{"amd64": "64bit", "arm64": "arm64"}
+	for _, target := range releaseTargets {
+		if target.GOOS != "windows" {
+			continue
+		}
+		archive := releaseArchiveName(releaseVersion, target.GOOS, target.GOARCH)
+		sum, ok := sums[archive]
+		if !ok {
+			return nil, fmt.Errorf("missing checksum for %s", archive)
+		}
+		manifest.Architecture[scoopArchNames[target.GOARCH]] = scoopArch{
+			URL:  baseURL + "/" + archive,
+			Hash: sum,
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReleaseArchivesAndChecksums(t *testing.T) {
+	tempDir := t.TempDir()
+	binary := filepath.Join(tempDir, "statusline")
+	if err := os.WriteFile(binary, []byte("binary"), 0755); err != nil {
+		t.Fatalf("Failed to create fake binary: %v", err)
+	}
+
+	files := map[string]string{"statusline": binary}
+	if err := writeTarGzArchive(filepath.Join(tempDir, "a.tar.gz"), files); err != nil {
+		t.Fatalf("writeTarGzArchive() failed: %v", err)
+	}
+	if err := writeZipArchive(filepath.Join(tempDir, "a.zip"), files); err != nil {
+		t.Fatalf("writeZipArchive() failed: %v", err)
+	}
+
+	zr, err := zip.OpenReader(filepath.Join(tempDir, "a.zip"))
+	if err != nil {
+		t.Fatalf("Failed to open zip archive: %v", err)
+	}
+	defer zr.Close()
+	if len(zr.File) != 1 || zr.File[0].Name != "statusline" {
+		t.Errorf("Unexpected zip contents: %v", zr.File)
+	}
+
+	if err := writeChecksums(tempDir, []string{"a.tar.gz", "a.zip"}); err != nil {
+		t.Fatalf("writeChecksums() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, releaseChecksumsFile))
+	if err != nil {
+		t.Fatalf("Failed to read checksums: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 checksum lines, got %d: %s", len(lines), content)
+	}
+
+	zipSum, _ := fileSHA256(filepath.Join(tempDir, "a.zip"))
+	if lines[1] != zipSum+"  a.zip" {
+		t.Errorf("Unexpected checksum line %q", lines[1])
+	}
+}
+
+func TestReleaseCommandRequiresVersion(t *testing.T) {
+	var stdout bytes.Buffer
+	err := run(&stdout, []string{"build"})
+	if err == nil || !strings.Contains(err.Error(), "Usage") {
+		t.Errorf("Expected usage error, got: %v", err)
+	}
+}
+
+func TestReleaseArchiveName(t *testing.T) {
+	tests := []struct {
+		version  string
+		goos     string
+		goarch   string
+		expected string
+	}{
+		{"v1.2.3", "darwin", "arm64", "statusline_1.2.3_darwin_arm64.tar.gz"},
+		{"1.2.3", "linux", "amd64", "statusline_1.2.3_linux_amd64.tar.gz"},
+		{"v0.1.0", "windows", "amd64", "statusline_0.1.0_windows_amd64.zip"},
+	}
+
+	for _, tt := range tests {
+		if got := releaseArchiveName(tt.version, tt.goos, tt.goarch); got != tt.expected {
+			t.Errorf("releaseArchiveName(%q, %q, %q) = %q, want %q", tt.version, tt.goos, tt.goarch, got, tt.expected)
+		}
+	}
+}
+
+func TestPackagesCommand(t *testing.T) {
+	distDir := t.TempDir()
+
+	var lines []string
+	for i, target := range releaseTargets {
+		archive := releaseArchiveName("v1.2.3", target.GOOS, target.GOARCH)
+		lines = append(lines, fmt.Sprintf("%064d  %s", i, archive))
+	}
+	checksums := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(distDir, releaseChecksumsFile), []byte(checksums), 0644); err != nil {
+		t.Fatalf("Failed to write checksums: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := run(&stdout, []string{"packages", "v1.2.3", distDir, "https://example.com/dl/"}); err != nil {
+		t.Fatalf("packages command failed: %v", err)
+	}
+
+	formula, err := os.ReadFile(filepath.Join(distDir, "statusline.rb"))
+	if err != nil {
+		t.Fatalf("Failed to read formula: %v", err)
+	}
+	for _, want := range []string{
+		`version "1.2.3"`,
+		"on_macos do",
+		"on_linux do",
+		`url "https://example.com/dl/statusline_1.2.3_darwin_arm64.tar.gz"`,
+		fmt.Sprintf(`sha256 "%064d"`, 1),
+	} {
+		if !strings.Contains(string(formula), want) {
+			t.Errorf("Formula missing %q:\n%s", want, formula)
+		}
+	}
+
+	manifestData, err := os.ReadFile(filepath.Join(distDir, "statusline.json"))
+	if err != nil {
+		t.Fatalf("Failed to read scoop manifest: %v", err)
+	}
+
+	var manifest scoopManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		t.Fatalf("Failed to parse scoop manifest: %v", err)
+	}
+	if manifest.Architecture["64bit"].URL != "https://example.com/dl/statusline_1.2.3_windows_amd64.zip" {
+		t.Errorf("Unexpected 64bit URL: %s", manifest.Architecture["64bit"].URL)
+	}
+	if manifest.Architecture["arm64"].Hash != fmt.Sprintf("%064d", 5) {
+		t.Errorf("Unexpected arm64 hash: %s", manifest.Architecture["arm64"].Hash)
+	}
+	if manifest.Bin != "statusline.exe" {
+		t.Errorf("Expected bin statusline.exe, got %s", manifest.Bin)
+	}
+}
+
+func TestPackagesCommandMissingChecksum(t *testing.T) {
+	distDir := t.TempDir()
+	os.WriteFile(filepath.Join(distDir, releaseChecksumsFile), []byte(""), 0644)
+
+	var stdout bytes.Buffer
+	err := run(&stdout, []string{"packages", "v1.2.3", distDir})
+	if err == nil || !strings.Contains(err.Error(), "missing checksum") {
+		t.Errorf("Expected missing checksum error, got: %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import "os"
+
+// ownedByCurrentUser cannot tell the owner here, so every repository is
+// taken as the user's own.
+func ownedByCurrentUser(info os.FileInfo) bool {
+	return true
+}
+
+// privateToCurrentUser cannot check ownership or permission bits here; the
+// temp directory of these systems is per user.
+func privateToCurrentUser(info os.FileInfo) bool {
+	return true
+}
@@ -0,0 +1,21 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownedByCurrentUser reports whether info belongs to the effective user, as
+// git's safe.directory check requires.
+func ownedByCurrentUser(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && stat.Uid == uint32(os.Geteuid())
+}
+
+// privateToCurrentUser reports whether info belongs to the effective user
+// and grants no access to the group or others.
+func privateToCurrentUser(info os.FileInfo) bool {
+	return ownedByCurrentUser(info) && info.Mode().Perm()&0o077 == 0
+}
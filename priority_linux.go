@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// ioprio_set arguments for the lowest best-effort I/O priority.
+const (
+	ioprioWhoProcess = 1
+	ioprioClassBE    = 2
+	ioprioClassShift = 13
+	ioprioLowest     = 7
+)
+
+// setNice lowers the CPU and I/O priority of every thread of this process.
+// Linux keeps both per thread; threads the runtime starts later inherit
+// them from the ones changed here.
+func setNice(nice int) error {
+	for _, tid := range processThreads(os.Getpid()) {
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, nice); err != nil {
+			return err
+		}
+		ioprio := uintptr(ioprioClassBE<<ioprioClassShift | ioprioLowest)
+		if _, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), ioprio); errno != 0 {
+			return errno
+		}
+	}
+	return nil
+}
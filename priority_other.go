@@ -0,0 +1,9 @@
+//go:build !(linux || windows || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import "errors"
+
+func setNice(nice int) error {
+	return errors.ErrUnsupported
+}
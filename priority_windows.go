@@ -0,0 +1,27 @@
+package main
+
+import "syscall"
+
+var procSetPriorityClass = syscall.NewLazyDLL("kernel32.dll").NewProc("SetPriorityClass")
+
+const (
+	belowNormalPriorityClass = 0x4000
+	idlePriorityClass        = 0x40
+)
+
+// setNice moves this process to the below normal priority class, or to
+// idle from a niceness of 15.
+func setNice(nice int) error {
+	class := uintptr(belowNormalPriorityClass)
+	if nice >= 15 {
+		class = idlePriorityClass
+	}
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return err
+	}
+	if ok, _, err := procSetPriorityClass.Call(uintptr(process), class); ok == 0 {
+		return err
+	}
+	return nil
+}